@@ -0,0 +1,182 @@
+package docx
+
+import (
+	"sort"
+	"strings"
+)
+
+// DelimiterPair is a single open/close delimiter pair a ParserConfig
+// recognises, e.g. {Open: "{{", Close: "}}"}.
+type DelimiterPair struct {
+	Open  string
+	Close string
+}
+
+// ParserConfig controls how ParsePlaceholders tokenizes run text: which
+// delimiter pairs bound a placeholder, which rune escapes a delimiter, and
+// which byte ranges of the document are "raw" (never scanned for
+// placeholders at all, e.g. a code sample that happens to contain `{`).
+//
+// Use DefaultParserConfig for the module's historical `{`/`}` behaviour, or
+// build a custom one with WithDelimiters / WithRaw.
+type ParserConfig struct {
+	// Delimiters lists every open/close pair the tokenizer recognises, tried
+	// longest-Open-first so a pair whose Open is a prefix of another's (e.g.
+	// "{" and "{{") never shadows the more specific one. Building this slice
+	// by hand, rather than through WithDelimiters, is the caller's
+	// responsibility to order correctly.
+	Delimiters []DelimiterPair
+
+	// Escape is the rune that, immediately preceding a delimiter, causes
+	// that delimiter to be treated as a literal and skipped. Zero disables
+	// escaping.
+	Escape rune
+
+	// Raw lists absolute byte ranges within the document that are excluded
+	// from placeholder scanning entirely.
+	Raw []Position
+}
+
+// DefaultParserConfig returns the ParserConfig matching the module's
+// historical behaviour: single-rune '{'/'}' delimiters with '\' escaping and
+// no raw ranges.
+func DefaultParserConfig() *ParserConfig {
+	return &ParserConfig{
+		Delimiters: []DelimiterPair{{Open: string(OpenDelimiter), Close: string(CloseDelimiter)}},
+		Escape:     '\\',
+	}
+}
+
+// WithDelimiters returns a copy of cfg with the given delimiter pair added.
+// Pairs are matched longest-Open-first regardless of the order they were
+// added in, so a pair whose Open is a prefix of another's (e.g. "{" and
+// "{{") can never shadow the more specific one.
+func (cfg *ParserConfig) WithDelimiters(open, close string) *ParserConfig {
+	next := cfg.clone()
+	pos := sort.Search(len(next.Delimiters), func(i int) bool {
+		return len(next.Delimiters[i].Open) < len(open)
+	})
+	next.Delimiters = append(next.Delimiters, DelimiterPair{})
+	copy(next.Delimiters[pos+1:], next.Delimiters[pos:])
+	next.Delimiters[pos] = DelimiterPair{Open: open, Close: close}
+	return next
+}
+
+// WithRaw returns a copy of cfg with the given absolute byte range marked as
+// raw, meaning it is skipped by the placeholder scanner.
+func (cfg *ParserConfig) WithRaw(start, end int64) *ParserConfig {
+	next := cfg.clone()
+	next.Raw = append(next.Raw, Position{Start: start, End: end})
+	return next
+}
+
+func (cfg *ParserConfig) clone() *ParserConfig {
+	next := *cfg
+	next.Delimiters = append([]DelimiterPair{}, cfg.Delimiters...)
+	next.Raw = append([]Position{}, cfg.Raw...)
+	return &next
+}
+
+// isRaw reports whether the absolute document offset pos falls inside one of
+// cfg's raw ranges.
+func (cfg *ParserConfig) isRaw(pos int64) bool {
+	for _, r := range cfg.Raw {
+		if pos >= r.Start && pos < r.End {
+			return true
+		}
+	}
+	return false
+}
+
+// delimiterMatch is a single open or close delimiter occurrence found while
+// tokenizing a run's text.
+type delimiterMatch struct {
+	pos    int // byte offset of the delimiter's first byte, relative to the run text
+	length int // byte length of the matched delimiter literal
+	open   bool
+}
+
+// scanDelimiters walks text with an escape-aware state machine and returns
+// every open/close delimiter occurrence in order. A delimiter immediately
+// preceded by cfg.Escape is treated as a literal and omitted from the
+// result.
+func (cfg *ParserConfig) scanDelimiters(text string) []delimiterMatch {
+	var matches []delimiterMatch
+	i := 0
+	for i < len(text) {
+		if cfg.Escape != 0 && rune(text[i]) == cfg.Escape && i+1 < len(text) {
+			if _, length, _, ok := cfg.matchAt(text, i+1); ok {
+				i += 1 + length
+				continue
+			}
+		}
+		if _, length, open, ok := cfg.matchAt(text, i); ok {
+			matches = append(matches, delimiterMatch{pos: i, length: length, open: open})
+			i += length
+			continue
+		}
+		i++
+	}
+	return matches
+}
+
+// matchAt reports whether one of cfg.Delimiters starts at byte offset i in
+// text, returning the matched literal, its length and whether it is an open
+// or close delimiter.
+func (cfg *ParserConfig) matchAt(text string, i int) (tok string, length int, open bool, ok bool) {
+	for _, pair := range cfg.Delimiters {
+		if pair.Open != "" && strings.HasPrefix(text[i:], pair.Open) {
+			return pair.Open, len(pair.Open), true, true
+		}
+		if pair.Close != "" && strings.HasPrefix(text[i:], pair.Close) {
+			return pair.Close, len(pair.Close), false, true
+		}
+	}
+	return "", 0, false, false
+}
+
+// findDelimiterPositions scans runText using cfg and returns, in order, the
+// start offsets of every open delimiter and the end offsets (exclusive, i.e.
+// one past the delimiter's last byte) of every close delimiter. Matches
+// falling inside a raw range are dropped; runOffset is the run text's
+// absolute offset in the document, used to resolve raw ranges.
+func (cfg *ParserConfig) findDelimiterPositions(runText string, runOffset int64) (openPos, closePos []int) {
+	for _, m := range cfg.scanDelimiters(runText) {
+		if cfg.isRaw(runOffset + int64(m.pos)) {
+			continue
+		}
+		if m.open {
+			openPos = append(openPos, m.pos)
+		} else {
+			closePos = append(closePos, m.pos+m.length)
+		}
+	}
+	return openPos, closePos
+}
+
+// IsDelimited reports whether s is wrapped in one of cfg's delimiter pairs,
+// e.g. under WithDelimiters("<%", "%>"), IsDelimited("<%foo%>") is true.
+// This is the ParserConfig-aware counterpart to IsDelimitedPlaceholder,
+// which only ever recognises the package-level single-rune '{'/'}' pair.
+func (cfg *ParserConfig) IsDelimited(s string) bool {
+	for _, pair := range cfg.Delimiters {
+		if len(s) >= len(pair.Open)+len(pair.Close) &&
+			strings.HasPrefix(s, pair.Open) && strings.HasSuffix(s, pair.Close) {
+			return true
+		}
+	}
+	return false
+}
+
+// StripDelimiters removes the first matching delimiter pair wrapping s. If s
+// isn't wrapped in any of cfg's delimiter pairs, it is returned unchanged.
+// This is the ParserConfig-aware counterpart to RemovePlaceholderDelimiter.
+func (cfg *ParserConfig) StripDelimiters(s string) string {
+	for _, pair := range cfg.Delimiters {
+		if len(s) >= len(pair.Open)+len(pair.Close) &&
+			strings.HasPrefix(s, pair.Open) && strings.HasSuffix(s, pair.Close) {
+			return s[len(pair.Open) : len(s)-len(pair.Close)]
+		}
+	}
+	return s
+}
@@ -0,0 +1,93 @@
+package docx
+
+import "testing"
+
+func TestParserConfigScanDelimitersDefault(t *testing.T) {
+	cfg := DefaultParserConfig()
+	matches := cfg.scanDelimiters(`say {name} and \{escaped\}`)
+
+	if len(matches) != 2 {
+		t.Fatalf("scanDelimiters found %d matches, want 2 (got %+v)", len(matches), matches)
+	}
+	if !matches[0].open || matches[0].pos != 4 {
+		t.Errorf("matches[0] = %+v, want open at pos 4", matches[0])
+	}
+	if matches[1].open || matches[1].pos != 9 {
+		t.Errorf("matches[1] = %+v, want close at pos 9", matches[1])
+	}
+}
+
+func TestParserConfigScanDelimitersNonBraceMultiChar(t *testing.T) {
+	cfg := (&ParserConfig{Escape: '\\'}).WithDelimiters("<%", "%>")
+	text := `say <%name%> and plain { braces } pass through`
+
+	matches := cfg.scanDelimiters(text)
+	if len(matches) != 2 {
+		t.Fatalf("scanDelimiters found %d matches, want 2 (got %+v)", len(matches), matches)
+	}
+	if !matches[0].open || matches[0].length != 2 {
+		t.Errorf("matches[0] = %+v, want a 2-byte open delimiter", matches[0])
+	}
+	if matches[1].open || matches[1].length != 2 {
+		t.Errorf("matches[1] = %+v, want a 2-byte close delimiter", matches[1])
+	}
+
+	openPos, closePos := cfg.findDelimiterPositions(text, 0)
+	if len(openPos) != 1 || len(closePos) != 1 {
+		t.Fatalf("findDelimiterPositions = %v, %v; want one open and one close", openPos, closePos)
+	}
+	got := text[openPos[0]:closePos[0]]
+	if got != "<%name%>" {
+		t.Errorf("delimited span = %q, want %q", got, "<%name%>")
+	}
+}
+
+func TestParserConfigIsDelimitedAndStripDelimiters(t *testing.T) {
+	cfg := (&ParserConfig{}).WithDelimiters("<%", "%>")
+
+	if !cfg.IsDelimited("<%name%>") {
+		t.Error("IsDelimited(\"<%name%>\") = false, want true")
+	}
+	if cfg.IsDelimited("{name}") {
+		t.Error(`IsDelimited("{name}") = true, want false (not one of cfg's delimiter pairs)`)
+	}
+	if got := cfg.StripDelimiters("<%name%>"); got != "name" {
+		t.Errorf(`StripDelimiters("<%%name%%>") = %q, want "name"`, got)
+	}
+	if got := cfg.StripDelimiters("name"); got != "name" {
+		t.Errorf(`StripDelimiters("name") = %q, want unchanged %q`, got, "name")
+	}
+}
+
+// TestParserConfigWithDelimitersLayersOverDefault exercises the exact
+// "register several delimiter pairs simultaneously" scenario WithDelimiters
+// is meant to support: layering a more specific pair on top of
+// DefaultParserConfig's bare '{'/'}' without the shorter pair shadowing it.
+func TestParserConfigWithDelimitersLayersOverDefault(t *testing.T) {
+	cfg := DefaultParserConfig().WithDelimiters("{{", "}}")
+
+	matches := cfg.scanDelimiters("say {{name}} now")
+	if len(matches) != 2 {
+		t.Fatalf("scanDelimiters found %d matches, want 2 (a single {{/}} pair), got %+v", len(matches), matches)
+	}
+	if !matches[0].open || matches[0].length != 2 {
+		t.Errorf("matches[0] = %+v, want a 2-byte open delimiter", matches[0])
+	}
+	if matches[1].open || matches[1].length != 2 {
+		t.Errorf("matches[1] = %+v, want a 2-byte close delimiter", matches[1])
+	}
+
+	// the bare '{'/'}' pair must still work for text that isn't doubled up.
+	matches = cfg.scanDelimiters("say {name} now")
+	if len(matches) != 2 || matches[0].length != 1 || matches[1].length != 1 {
+		t.Errorf("scanDelimiters(%q) = %+v, want a single-byte open/close pair", "say {name} now", matches)
+	}
+}
+
+func TestParserConfigEscapedDelimiterIsLiteral(t *testing.T) {
+	cfg := DefaultParserConfig()
+	matches := cfg.scanDelimiters(`\{not a placeholder\}`)
+	if len(matches) != 0 {
+		t.Fatalf("scanDelimiters found %d matches for escaped delimiters, want 0 (got %+v)", len(matches), matches)
+	}
+}
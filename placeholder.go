@@ -2,7 +2,6 @@ package docx
 
 import (
 	"fmt"
-	"regexp"
 	"strings"
 )
 
@@ -11,11 +10,6 @@ const (
 	CloseDelimiter rune = '}'
 )
 
-var (
-	OpenDelimiterRegex = regexp.MustCompile(string(OpenDelimiter))
-	CloseDelimiterRegex = regexp.MustCompile(string(CloseDelimiter))
-)
-
 // PlaceholderMap is the type used to map the placeholder keys (without delimiters) to the replacement values
 type PlaceholderMap map[string]interface{}
 
@@ -46,8 +40,12 @@ func (p Placeholder) EndPos() int64 {
 }
 
 // ParsePlaceholders will, given the document run positions and the bytes, parse out all placeholders including
-// their fragments.
-func ParsePlaceholders(runs DocumentRuns, docBytes []byte) (placeholders []*Placeholder) {
+// their fragments. cfg controls the delimiters recognised by the scanner; if cfg is nil, DefaultParserConfig is used.
+func ParsePlaceholders(runs DocumentRuns, docBytes []byte, cfg *ParserConfig) (placeholders []*Placeholder) {
+	if cfg == nil {
+		cfg = DefaultParserConfig()
+	}
+
 	// tmp vars used to preserve state across iterations
 	unclosedPlaceholder := new(Placeholder)
 	hasOpenPlaceholder := false
@@ -55,22 +53,9 @@ func ParsePlaceholders(runs DocumentRuns, docBytes []byte) (placeholders []*Plac
 	for _, run := range runs.WithText() {
 		runText := run.GetText(docBytes)
 
-		openDelimPositions := OpenDelimiterRegex.FindAllStringIndex(runText, -1)
-		closeDelimPositions := CloseDelimiterRegex.FindAllStringIndex(runText, -1)
-
-		// FindAllStringIndex returns a [][]int whereas the nested []int has only 2 keys (0 and 1)
-		// We're only interested in the first key as that one indicates the position of the delimiter
-		delimPositions := func(positions [][]int) []int {
-			var pos []int
-			for _, position := range positions {
-				pos = append(pos, position[0])
-			}
-			return pos
-		}
-
-		// index all delimiters
-		openPos := delimPositions(openDelimPositions)
-		closePos := delimPositions(closeDelimPositions)
+		// index all delimiters; closePos entries are already the exclusive end
+		// of the matched close delimiter (i.e. one past its last byte).
+		openPos, closePos := cfg.findDelimiterPositions(runText, run.Text.StartTag.End)
 
 		// simple case: only full placeholders inside the run
 		if (len(openPos) == len(closePos)) && len(openPos) != 0 {
@@ -78,15 +63,25 @@ func ParsePlaceholders(runs DocumentRuns, docBytes []byte) (placeholders []*Plac
 			continue
 		}
 
-		// more open than closing delimiters
-		// this can only mean that a placeholder is left unclosed after this run
-		// For the length this must mean: (len(openPos) + 1) == len(closePos)
-		// So we can be sure that the last position in openPos is the opening tag of the
-		// unclosed placeholder.
+		// more open than closing delimiters: some number of opens are left
+		// unclosed after this run. Only the last of them, in document order,
+		// can possibly be closed by a later run (this package doesn't support
+		// nested placeholders, so there is only ever one pending-close slot);
+		// any earlier stray opens have nothing left in this run to close them
+		// and become single-delimiter fragments that the both-delimiters-
+		// present filter below drops.
 		if len(openPos) > len(closePos) {
-			// merge full placeholders in the run, leaving out the last openPos since
-			// we know that the one is left over and must be handled separately below
-			placeholders = append(placeholders, assembleFullPlaceholders(run, openPos[:len(openPos)-1], closePos)...)
+			matched := len(closePos)
+			placeholders = append(placeholders, assembleFullPlaceholders(run, openPos[:matched], closePos)...)
+
+			for _, pos := range openPos[matched : len(openPos)-1] {
+				fragment := &PlaceholderFragment{
+					Position: Position{Start: int64(pos), End: int64(len(runText))},
+					Number:   0,
+					Run:      run,
+				}
+				placeholders = append(placeholders, &Placeholder{Fragments: []*PlaceholderFragment{fragment}})
+			}
 
 			// add the unclosed part of the placeholder to a tmp placeholder var
 			unclosedOpenPos := openPos[len(openPos)-1]
@@ -103,20 +98,22 @@ func ParsePlaceholders(runs DocumentRuns, docBytes []byte) (placeholders []*Plac
 			continue
 		}
 
-		// more closing than opening delimiters
-		// this can only mean that there must be an unclosed placeholder which
-		// is closed in this run.
+		// more closing than opening delimiters: every open in this run is
+		// matched against its close below, leaving some number of closes with
+		// nothing left to pair with in this run. Those can only be closing a
+		// placeholder carried over from an earlier run, which is only
+		// possible if this run has no opens of its own at all; the first of
+		// them then closes it, and any further excess closes have nothing
+		// left to close and are ignored outright.
 		if len(openPos) < len(closePos) {
-			// merge full placeholders in the run, leaving out the last closePos since
-			// we know that the one is left over and must be handled separately below
-			placeholders = append(placeholders, assembleFullPlaceholders(run, openPos, closePos[:len(closePos) - 1])...)
+			matched := len(openPos)
+			placeholders = append(placeholders, assembleFullPlaceholders(run, openPos, closePos[:matched])...)
 
-			// there is only a closePos and no open pos
-			if len(closePos) == 1 {
+			if len(openPos) == 0 {
 				fragment := &PlaceholderFragment{
 					Position: Position{
 						Start: 0,
-						End:   int64(closePos[0])+1,
+						End:   int64(closePos[0]),
 					},
 					Number: len(unclosedPlaceholder.Fragments) + 1,
 					Run:    run,
@@ -125,7 +122,6 @@ func ParsePlaceholders(runs DocumentRuns, docBytes []byte) (placeholders []*Plac
 				placeholders = append(placeholders, unclosedPlaceholder)
 				unclosedPlaceholder = new(Placeholder)
 				hasOpenPlaceholder = false
-				continue
 			}
 			continue
 		}
@@ -149,27 +145,35 @@ func ParsePlaceholders(runs DocumentRuns, docBytes []byte) (placeholders []*Plac
 	}
 
 	// in order to catch false positives, ensure that all placeholders have BOTH delimiters
-	// if a placeholder only has one, remove it since it cannot be right.
-	for i, placeholder := range placeholders {
+	// if a placeholder only has one, drop it since it cannot be right. Kept placeholders are
+	// copied forward into the same backing array (kept := placeholders[:0]) rather than
+	// removed with append while ranging, which skips the element shifted into the removed index.
+	kept := placeholders[:0]
+	for _, placeholder := range placeholders {
 		text := placeholder.Text(docBytes)
-		if !strings.ContainsRune(text, OpenDelimiter) ||
-			!strings.ContainsRune(text, CloseDelimiter) {
-			placeholders = append(placeholders[:i], placeholders[i+1:]...)
+		hasOpen, hasClose := false, false
+		for _, pair := range cfg.Delimiters {
+			hasOpen = hasOpen || strings.Contains(text, pair.Open)
+			hasClose = hasClose || strings.Contains(text, pair.Close)
+		}
+		if hasOpen && hasClose {
+			kept = append(kept, placeholder)
 		}
 	}
 
-	return placeholders
+	return kept
 }
 
 // assembleFullPlaceholders will extract all complete placeholders inside the run given a open and close position.
 // The open and close positions are the positions of the Delimiters which must already be known at this point.
-// openPos and closePos are expected to be symmetrical (e.g. same length).
-// Example: openPos := []int{10,20,30}; closePos := []int{13, 23, 33}
+// openPos and closePos are expected to be symmetrical (e.g. same length). closePos entries are already the
+// exclusive end of the close delimiter (i.e. one past its last byte), so no further adjustment is needed here.
+// Example: openPos := []int{10,20,30}; closePos := []int{14, 24, 34}
 // The n-th elements inside openPos and closePos must be matching delimiter positions.
 func assembleFullPlaceholders(run *Run, openPos, closePos []int) (placeholders []*Placeholder){
 	for i := 0; i < len(openPos); i++ {
 		start := openPos[i]
-		end := closePos[i] + 1 // +1 is required to include the closing delimiter in the text
+		end := closePos[i]
 		fragment := &PlaceholderFragment{
 			Position: Position{
 				Start: int64(start),
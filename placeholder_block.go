@@ -0,0 +1,263 @@
+package docx
+
+import (
+	"fmt"
+	"strings"
+)
+
+// BlockKind identifies the kind of block-level directive a Block represents.
+type BlockKind int
+
+const (
+	// BlockEach is a `{#each items}` ... `{/each}` loop.
+	BlockEach BlockKind = iota
+	// BlockIf is a `{#if cond}` ... `{else}` ... `{/if}` conditional.
+	BlockIf
+	// BlockRow is a `{#row items}` ... `{/row}` loop that repeats the
+	// enclosing table row rather than a paragraph.
+	BlockRow
+)
+
+func (k BlockKind) String() string {
+	switch k {
+	case BlockEach:
+		return "each"
+	case BlockIf:
+		return "if"
+	case BlockRow:
+		return "row"
+	default:
+		return "unknown"
+	}
+}
+
+// Block is a paired opening/closing directive placeholder, along with the
+// placeholders found in its body. ParseBlocks builds Blocks out of the flat
+// []*Placeholder list returned by ParsePlaceholders; it is up to the
+// replacer to act on a Block by cloning or dropping the paragraph/row XML
+// nodes the Open/Close placeholders' runs belong to.
+//
+// Only BlockIf uses ElseMarker/ElseBody; for BlockEach and BlockRow the
+// entire body between Open and Close is in Body.
+//
+// Block only has access to the *Run each of its placeholders was found in
+// (via Runs); there is no Paragraph/Row type in this package yet to carry a
+// stronger reference to the enclosing XML node, and no Document.Replace to
+// call Runs from. That means a replacer cannot yet clone or drop the right
+// XML subtree from a Block alone -- Runs is a building block for that, not
+// a substitute for it. Wiring BlockRow up to an actual table row, and
+// BlockEach/BlockIf up to an actual paragraph, is tracked as follow-up work
+// once those types exist.
+type Block struct {
+	Kind BlockKind
+
+	// Expr is the collection (`{#each items}`) or condition (`{#if cond}`)
+	// expression. It is nil if the directive's argument failed to parse.
+	Expr *PlaceholderExpr
+
+	Open       *Placeholder
+	Close      *Placeholder
+	ElseMarker *Placeholder // non-nil only for a BlockIf with an `{else}`
+
+	Body     []*Placeholder // body placeholders (the "then" branch, for BlockIf)
+	ElseBody []*Placeholder // the "else" branch, for BlockIf only
+}
+
+// ParseBlocks pairs block directive placeholders (`{#each}`, `{#if}`,
+// `{else}`, `{#row}` and their closing counterparts) found in placeholders
+// into a flat list of Blocks, in the order their closing directives are
+// found (document order, for blocks that are not nested). Plain (non-directive)
+// placeholders are attached to the Body/ElseBody of whichever block
+// currently encloses them.
+//
+// This version does not support nesting one block inside another: an
+// opening directive found while a block is already open is reported as a
+// PlaceholderError of kind NestedPlaceholder and the offending block is still parsed and paired
+// (so a single stray directive doesn't cascade into spurious unclosed-block
+// errors), but it will not appear as a child of its enclosing block.
+//
+// cfg controls the delimiters recognised when stripping each placeholder
+// down to its directive text; if cfg is nil, DefaultParserConfig is used.
+// It must be the same ParserConfig the placeholders were parsed with, or
+// directives written with non-default delimiters won't be recognised.
+func ParseBlocks(placeholders []*Placeholder, docBytes []byte, cfg *ParserConfig) (blocks []*Block, errs []*PlaceholderError) {
+	if cfg == nil {
+		cfg = DefaultParserConfig()
+	}
+
+	var stack []*Block
+	var inElse []bool
+
+	for _, ph := range placeholders {
+		text := strings.TrimSpace(cfg.StripDelimiters(ph.Text(docBytes)))
+		kind, arg, isOpen, isClose, isElse := classifyDirective(text)
+
+		switch {
+		case isOpen:
+			if len(stack) != 0 {
+				errs = append(errs, &PlaceholderError{
+					Kind:    NestedPlaceholder,
+					Offset:  ph.StartPos(),
+					Snippet: snippet(docBytes, ph.StartPos()),
+				})
+			}
+			blk := &Block{Kind: kind, Open: ph}
+			if expr, err := ParsePlaceholderExpr(arg); err == nil {
+				blk.Expr = expr
+			}
+			stack = append(stack, blk)
+			inElse = append(inElse, false)
+
+		case isElse:
+			if len(stack) == 0 || stack[len(stack)-1].Kind != BlockIf {
+				errs = append(errs, &PlaceholderError{
+					Kind:    UnopenedDelimiter,
+					Offset:  ph.StartPos(),
+					Snippet: snippet(docBytes, ph.StartPos()),
+				})
+				continue
+			}
+			stack[len(stack)-1].ElseMarker = ph
+			inElse[len(inElse)-1] = true
+
+		case isClose:
+			if len(stack) == 0 || stack[len(stack)-1].Kind != kind {
+				errs = append(errs, &PlaceholderError{
+					Kind:    UnopenedDelimiter,
+					Offset:  ph.StartPos(),
+					Snippet: snippet(docBytes, ph.StartPos()),
+				})
+				continue
+			}
+			top := len(stack) - 1
+			blk := stack[top]
+			blk.Close = ph
+			stack = stack[:top]
+			inElse = inElse[:top]
+			blocks = append(blocks, blk)
+
+		default:
+			if len(stack) == 0 {
+				continue
+			}
+			top := stack[len(stack)-1]
+			if top.Kind == BlockIf && inElse[len(inElse)-1] {
+				top.ElseBody = append(top.ElseBody, ph)
+			} else {
+				top.Body = append(top.Body, ph)
+			}
+		}
+	}
+
+	for _, blk := range stack {
+		errs = append(errs, &PlaceholderError{
+			Kind:    UnclosedDelimiter,
+			Offset:  blk.Open.StartPos(),
+			Snippet: snippet(docBytes, blk.Open.StartPos()),
+		})
+	}
+
+	return blocks, errs
+}
+
+// classifyDirective reports whether text (a placeholder's body with
+// delimiters already stripped) is a block directive, and if so, which kind
+// of directive it is and its argument, if any.
+func classifyDirective(text string) (kind BlockKind, arg string, isOpen, isClose, isElse bool) {
+	switch {
+	case strings.HasPrefix(text, "#each "):
+		return BlockEach, strings.TrimSpace(strings.TrimPrefix(text, "#each ")), true, false, false
+	case strings.HasPrefix(text, "#if "):
+		return BlockIf, strings.TrimSpace(strings.TrimPrefix(text, "#if ")), true, false, false
+	case strings.HasPrefix(text, "#row "):
+		return BlockRow, strings.TrimSpace(strings.TrimPrefix(text, "#row ")), true, false, false
+	case text == "/each":
+		return BlockEach, "", false, true, false
+	case text == "/if":
+		return BlockIf, "", false, true, false
+	case text == "/row":
+		return BlockRow, "", false, true, false
+	case text == "else":
+		return 0, "", false, false, true
+	default:
+		return 0, "", false, false, false
+	}
+}
+
+// Iterations evaluates Expr as a collection against scope and returns one
+// child Scope per element, for the caller to evaluate blk.Body once each.
+// It only applies to BlockEach and BlockRow; calling it on a BlockIf is an error.
+func (b *Block) Iterations(scope *Scope) ([]*Scope, error) {
+	if b.Kind == BlockIf {
+		return nil, fmt.Errorf("docx: Iterations called on a BlockIf")
+	}
+	if b.Expr == nil {
+		return nil, fmt.Errorf("docx: block %s has no valid expression", b.Kind)
+	}
+	value, err := b.Expr.EvalScope(scope, nil)
+	if err != nil {
+		return nil, err
+	}
+	items, ok := toSlice(value)
+	if !ok {
+		return nil, fmt.Errorf("docx: block %s expression did not evaluate to a slice", b.Kind)
+	}
+	scopes := make([]*Scope, len(items))
+	for i, item := range items {
+		if m, ok := asMap(item); ok {
+			scopes[i] = scope.Child(PlaceholderMap(m))
+		} else {
+			// a primitive element (string, number, ...): `.` resolves straight to it.
+			scopes[i] = scope.Child(item)
+		}
+	}
+	return scopes, nil
+}
+
+// Runs returns, in document order and with consecutive duplicates removed,
+// every *Run referenced by the block's Open, Body, ElseMarker and ElseBody
+// placeholders, and its Close. A replacer still needs a way to map each of
+// these back to its enclosing paragraph or table row before it can clone
+// (BlockEach/BlockRow) or conditionally drop (BlockIf) the right XML
+// subtree; Runs does not provide that on its own.
+func (b *Block) Runs() []*Run {
+	var runs []*Run
+	add := func(phs ...*Placeholder) {
+		for _, ph := range phs {
+			if ph == nil {
+				continue
+			}
+			for _, frag := range ph.Fragments {
+				if len(runs) == 0 || runs[len(runs)-1] != frag.Run {
+					runs = append(runs, frag.Run)
+				}
+			}
+		}
+	}
+	add(b.Open)
+	add(b.Body...)
+	add(b.ElseMarker)
+	add(b.ElseBody...)
+	add(b.Close)
+	return runs
+}
+
+// Branch evaluates Expr as a boolean condition against scope and reports
+// which of Body (true) or ElseBody (false) applies. It only applies to
+// BlockIf; calling it on a BlockEach or BlockRow is an error.
+func (b *Block) Branch(scope *Scope) ([]*Placeholder, error) {
+	if b.Kind != BlockIf {
+		return nil, fmt.Errorf("docx: Branch called on a %s block", b.Kind)
+	}
+	if b.Expr == nil {
+		return nil, fmt.Errorf("docx: if block has no valid condition")
+	}
+	value, err := b.Expr.EvalScope(scope, nil)
+	if err != nil {
+		return nil, err
+	}
+	if isTruthy(value) {
+		return b.Body, nil
+	}
+	return b.ElseBody, nil
+}
@@ -0,0 +1,176 @@
+package docx
+
+import "testing"
+
+// TestBlockEachOverPrimitives exercises the `{#each items}{.}{/each}` case
+// the chunk0-5 request calls out explicitly: iterating a slice of
+// primitives and resolving `.` to the current element, rather than to a
+// map key named ".".
+func TestBlockEachOverPrimitives(t *testing.T) {
+	itemsExpr, err := ParsePlaceholderExpr("items")
+	if err != nil {
+		t.Fatalf("ParsePlaceholderExpr(%q) returned error: %v", "items", err)
+	}
+	dotExpr, err := ParsePlaceholderExpr(".")
+	if err != nil {
+		t.Fatalf(`ParsePlaceholderExpr(".") returned error: %v`, err)
+	}
+
+	blk := &Block{Kind: BlockEach, Expr: itemsExpr}
+	scope := NewScope(PlaceholderMap{"items": []interface{}{"a", "b"}})
+
+	scopes, err := blk.Iterations(scope)
+	if err != nil {
+		t.Fatalf("Iterations returned error: %v", err)
+	}
+	if len(scopes) != 2 {
+		t.Fatalf("Iterations returned %d scopes, want 2", len(scopes))
+	}
+
+	want := []string{"a", "b"}
+	for i, s := range scopes {
+		got, err := dotExpr.EvalScope(s, nil)
+		if err != nil {
+			t.Fatalf("EvalScope(%q) returned error: %v", ".", err)
+		}
+		if got != want[i] {
+			t.Errorf("iteration %d: got %v, want %q", i, got, want[i])
+		}
+	}
+}
+
+// TestBlockEachOverMapsWithParentScope exercises "..", the sibling case to
+// "." in the chunk0-5 scope stack: a map-valued loop element whose body can
+// still reach the enclosing scope's data.
+func TestBlockEachOverMapsWithParentScope(t *testing.T) {
+	itemsExpr, err := ParsePlaceholderExpr("items")
+	if err != nil {
+		t.Fatalf("ParsePlaceholderExpr(%q) returned error: %v", "items", err)
+	}
+	nameExpr, err := ParsePlaceholderExpr("name")
+	if err != nil {
+		t.Fatalf("ParsePlaceholderExpr(%q) returned error: %v", "name", err)
+	}
+	parentExpr, err := ParsePlaceholderExpr("..title")
+	if err != nil {
+		t.Fatalf(`ParsePlaceholderExpr("..title") returned error: %v`, err)
+	}
+
+	blk := &Block{Kind: BlockEach, Expr: itemsExpr}
+	scope := NewScope(PlaceholderMap{
+		"title": "Invoice",
+		"items": []interface{}{
+			PlaceholderMap{"name": "Widget"},
+		},
+	})
+
+	scopes, err := blk.Iterations(scope)
+	if err != nil {
+		t.Fatalf("Iterations returned error: %v", err)
+	}
+	if len(scopes) != 1 {
+		t.Fatalf("Iterations returned %d scopes, want 1", len(scopes))
+	}
+
+	if got, err := nameExpr.EvalScope(scopes[0], nil); err != nil || got != "Widget" {
+		t.Errorf(`EvalScope("name") = %v, %v; want "Widget", nil`, got, err)
+	}
+	if got, err := parentExpr.EvalScope(scopes[0], nil); err != nil || got != "Invoice" {
+		t.Errorf(`EvalScope("..title") = %v, %v; want "Invoice", nil`, got, err)
+	}
+}
+
+// TestBlockRowIterations exercises BlockRow, which shares Iterations' code
+// path with BlockEach but repeats a table row rather than a paragraph.
+func TestBlockRowIterations(t *testing.T) {
+	rowsExpr, err := ParsePlaceholderExpr("rows")
+	if err != nil {
+		t.Fatalf("ParsePlaceholderExpr(%q) returned error: %v", "rows", err)
+	}
+	cellExpr, err := ParsePlaceholderExpr("cell")
+	if err != nil {
+		t.Fatalf("ParsePlaceholderExpr(%q) returned error: %v", "cell", err)
+	}
+
+	blk := &Block{Kind: BlockRow, Expr: rowsExpr}
+	scope := NewScope(PlaceholderMap{
+		"rows": []interface{}{
+			PlaceholderMap{"cell": "A1"},
+			PlaceholderMap{"cell": "A2"},
+		},
+	})
+
+	scopes, err := blk.Iterations(scope)
+	if err != nil {
+		t.Fatalf("Iterations returned error: %v", err)
+	}
+	want := []string{"A1", "A2"}
+	if len(scopes) != len(want) {
+		t.Fatalf("Iterations returned %d scopes, want %d", len(scopes), len(want))
+	}
+	for i, s := range scopes {
+		got, err := cellExpr.EvalScope(s, nil)
+		if err != nil {
+			t.Fatalf("EvalScope(%q) returned error: %v", "cell", err)
+		}
+		if got != want[i] {
+			t.Errorf("row %d: got %v, want %q", i, got, want[i])
+		}
+	}
+}
+
+// TestBlockRuns exercises Runs collecting every *Run spanned by a block,
+// in order, with consecutive duplicates (a directive and the plain
+// placeholder sharing its run) collapsed.
+func TestBlockRuns(t *testing.T) {
+	openRun := &Run{}
+	bodyRun := &Run{}
+	closeRun := &Run{}
+
+	open := &Placeholder{Fragments: []*PlaceholderFragment{{Run: openRun}}}
+	body := &Placeholder{Fragments: []*PlaceholderFragment{{Run: openRun}, {Run: bodyRun}}}
+	close_ := &Placeholder{Fragments: []*PlaceholderFragment{{Run: closeRun}}}
+
+	blk := &Block{Kind: BlockEach, Open: open, Body: []*Placeholder{body}, Close: close_}
+
+	got := blk.Runs()
+	want := []*Run{openRun, bodyRun, closeRun}
+	if len(got) != len(want) {
+		t.Fatalf("Runs() returned %d runs, want %d", len(got), len(want))
+	}
+	for i, r := range want {
+		if got[i] != r {
+			t.Errorf("Runs()[%d] = %p, want %p", i, got[i], r)
+		}
+	}
+}
+
+// TestBlockIfBranch exercises Branch choosing Body vs ElseBody.
+func TestBlockIfBranch(t *testing.T) {
+	condExpr, err := ParsePlaceholderExpr("active")
+	if err != nil {
+		t.Fatalf("ParsePlaceholderExpr(%q) returned error: %v", "active", err)
+	}
+
+	then := []*Placeholder{{}}
+	els := []*Placeholder{{}, {}}
+	blk := &Block{Kind: BlockIf, Expr: condExpr, Body: then, ElseBody: els}
+
+	scope := NewScope(PlaceholderMap{"active": true})
+	got, err := blk.Branch(scope)
+	if err != nil {
+		t.Fatalf("Branch returned error: %v", err)
+	}
+	if len(got) != len(then) {
+		t.Errorf("Branch(active=true) returned %d placeholders, want %d (the then-branch)", len(got), len(then))
+	}
+
+	scope = NewScope(PlaceholderMap{"active": false})
+	got, err = blk.Branch(scope)
+	if err != nil {
+		t.Fatalf("Branch returned error: %v", err)
+	}
+	if len(got) != len(els) {
+		t.Errorf("Branch(active=false) returned %d placeholders, want %d (the else-branch)", len(got), len(els))
+	}
+}
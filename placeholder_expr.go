@@ -0,0 +1,679 @@
+package docx
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ExprKind identifies the kind of node inside a PlaceholderExpr AST.
+type ExprKind int
+
+const (
+	// ExprPath is a dotted key lookup, e.g. `user.name`.
+	ExprPath ExprKind = iota
+	// ExprIndex is a single-element slice/array access, e.g. `items[0]`.
+	ExprIndex
+	// ExprRange is a slice/array range access, e.g. `items[0..3]`.
+	ExprRange
+	// ExprPipeline applies one or more filters to a target expression, e.g. `price|format:"%.2f"`.
+	ExprPipeline
+	// ExprTernary is a conditional expression, e.g. `cond?"yes":"no"`.
+	ExprTernary
+	// ExprLiteral is a quoted string literal, e.g. the "yes" in
+	// `cond?"yes":"no"`, used as a ternary branch or as a pipeline target.
+	// Filter arguments are kept as plain strings in ExprFilter.Args, not as
+	// ExprLiteral nodes.
+	ExprLiteral
+)
+
+// ExprFilter is a single stage of an ExprPipeline, e.g. `upper` or `format:"%.2f"`.
+type ExprFilter struct {
+	Name string
+	Args []string
+}
+
+// PlaceholderExpr is the parsed representation of a placeholder body, e.g. the
+// `user.name` in `{user.name}` or the full `price|format:"%.2f"` pipeline in
+// `{price|format:"%.2f"}`. It is parsed once per placeholder at replace time
+// via ParsePlaceholderExpr and then evaluated against a PlaceholderMap with Eval.
+type PlaceholderExpr struct {
+	Kind ExprKind
+
+	// Path holds the dotted key segments for ExprPath nodes, e.g. []string{"user", "name"}.
+	Path []string
+
+	// Target is the expression being indexed, ranged or piped through filters.
+	// Used by ExprIndex, ExprRange and ExprPipeline.
+	Target *PlaceholderExpr
+
+	// Index is the slice/array index for ExprIndex nodes.
+	Index int
+
+	// RangeStart and RangeEnd are the bounds of an ExprRange node (`items[0..3]`).
+	RangeStart, RangeEnd int
+
+	// Filters holds the pipeline stages of an ExprPipeline node, applied left to right.
+	Filters []ExprFilter
+
+	// Cond, Then and Else make up an ExprTernary node (`cond?then:else`).
+	Cond, Then, Else *PlaceholderExpr
+
+	// Literal holds the decoded value of an ExprLiteral node.
+	Literal interface{}
+}
+
+// exprParser turns a placeholder body (the text between the delimiters,
+// without the delimiters themselves) into a PlaceholderExpr tree.
+type exprParser struct {
+	input string
+	pos   int
+}
+
+// ParsePlaceholderExpr parses the body of a placeholder (e.g. the `user.name`
+// in `{user.name}`) into a PlaceholderExpr. It supports dotted paths, index
+// and range access on slices (`items[0]`, `items[0..3]`), filter pipelines
+// (`price|format:"%.2f"`, `name:upper`) and ternary expressions
+// (`cond?"yes":"no"`).
+func ParsePlaceholderExpr(body string) (*PlaceholderExpr, error) {
+	p := &exprParser{input: strings.TrimSpace(body)}
+	if p.input == "" {
+		return nil, fmt.Errorf("docx: empty placeholder expression")
+	}
+	expr, err := p.parseTernary()
+	if err != nil {
+		return nil, fmt.Errorf("docx: parsing placeholder expression %q: %w", body, err)
+	}
+	p.skipSpace()
+	if p.pos != len(p.input) {
+		return nil, fmt.Errorf("docx: unexpected trailing input %q in placeholder expression %q", p.input[p.pos:], body)
+	}
+	return expr, nil
+}
+
+func (p *exprParser) parseTernary() (*PlaceholderExpr, error) {
+	cond, err := p.parsePipeline()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if !p.consume('?') {
+		return cond, nil
+	}
+	then, err := p.parseTernaryBranch()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if !p.consume(':') {
+		return nil, fmt.Errorf("expected ':' in ternary expression")
+	}
+	elseExpr, err := p.parseTernaryBranch()
+	if err != nil {
+		return nil, err
+	}
+	return &PlaceholderExpr{Kind: ExprTernary, Cond: cond, Then: then, Else: elseExpr}, nil
+}
+
+// parseTernaryBranch parses a primary expression followed by zero or more
+// '|'-separated filter stages, for use as a ternary's then/else branch.
+// Unlike parsePipeline, it doesn't accept the alternate ':'-separated filter
+// syntax (`name:upper`): a bare top-level ':' there would be ambiguous with
+// the ternary's own then/else separator (`cond?then:else`). A filter
+// introduced with '|' can still take ':'-separated arguments as usual
+// (`then|format:"%.2f"`), since that ':' is bound to the filter name, not to
+// the ternary.
+func (p *exprParser) parseTernaryBranch() (*PlaceholderExpr, error) {
+	target, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	var filters []ExprFilter
+	for {
+		p.skipSpace()
+		if !p.peekAny('|') {
+			break
+		}
+		p.pos++ // consume '|'
+		filter, err := p.parseFilter()
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, filter)
+	}
+	if len(filters) == 0 {
+		return target, nil
+	}
+	return &PlaceholderExpr{Kind: ExprPipeline, Target: target, Filters: filters}, nil
+}
+
+// parsePipeline parses a primary expression followed by zero or more filter
+// stages, separated by either `|` (`price|format:"%.2f"`) or `:` (`name:upper`).
+func (p *exprParser) parsePipeline() (*PlaceholderExpr, error) {
+	target, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+
+	var filters []ExprFilter
+	for {
+		p.skipSpace()
+		if !p.peekAny('|', ':') {
+			break
+		}
+		p.pos++ // consume '|' or ':'
+		filter, err := p.parseFilter()
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, filter)
+	}
+	if len(filters) == 0 {
+		return target, nil
+	}
+	return &PlaceholderExpr{Kind: ExprPipeline, Target: target, Filters: filters}, nil
+}
+
+func (p *exprParser) parseFilter() (ExprFilter, error) {
+	name := p.parseIdent()
+	if name == "" {
+		return ExprFilter{}, fmt.Errorf("expected filter name")
+	}
+	filter := ExprFilter{Name: name}
+
+	// Args are either `name(arg1, arg2)` or `name:arg1:arg2`.
+	if p.consume('(') {
+		for {
+			p.skipSpace()
+			if p.consume(')') {
+				break
+			}
+			if len(filter.Args) > 0 && !p.consume(',') {
+				return ExprFilter{}, fmt.Errorf("expected ',' between filter arguments")
+			}
+			p.skipSpace()
+			arg, err := p.parseArg()
+			if err != nil {
+				return ExprFilter{}, err
+			}
+			filter.Args = append(filter.Args, arg)
+		}
+		return filter, nil
+	}
+	for p.consume(':') {
+		arg, err := p.parseArg()
+		if err != nil {
+			return ExprFilter{}, err
+		}
+		filter.Args = append(filter.Args, arg)
+	}
+	return filter, nil
+}
+
+// parseArg parses a single filter argument, either a quoted string or a bare token.
+func (p *exprParser) parseArg() (string, error) {
+	p.skipSpace()
+	if p.pos < len(p.input) && p.input[p.pos] == '"' {
+		return p.parseQuoted()
+	}
+	start := p.pos
+	for p.pos < len(p.input) && !strings.ContainsRune(",)|: ", rune(p.input[p.pos])) {
+		p.pos++
+	}
+	if p.pos == start {
+		return "", fmt.Errorf("expected filter argument")
+	}
+	return p.input[start:p.pos], nil
+}
+
+func (p *exprParser) parseQuoted() (string, error) {
+	p.pos++ // opening quote
+	start := p.pos
+	for p.pos < len(p.input) && p.input[p.pos] != '"' {
+		if p.input[p.pos] == '\\' && p.pos+1 < len(p.input) {
+			p.pos++
+		}
+		p.pos++
+	}
+	if p.pos >= len(p.input) {
+		return "", fmt.Errorf("unterminated string literal")
+	}
+	s := p.input[start:p.pos]
+	p.pos++ // closing quote
+	return strings.ReplaceAll(s, `\"`, `"`), nil
+}
+
+// parsePrimary parses a path, optionally followed by an index or range access.
+func (p *exprParser) parsePrimary() (*PlaceholderExpr, error) {
+	p.skipSpace()
+	if p.pos < len(p.input) && p.input[p.pos] == '"' {
+		s, err := p.parseQuoted()
+		if err != nil {
+			return nil, err
+		}
+		return &PlaceholderExpr{Kind: ExprLiteral, Literal: s}, nil
+	}
+
+	var path []string
+	switch {
+	case p.consume2('.', '.'):
+		// "..": the enclosing (parent) scope's whole value, e.g. inside an {#each}.
+		path = []string{".."}
+	case p.consume('.'):
+		// ".": the current scope's whole value, e.g. the loop element itself
+		// when iterating a slice of primitives with {#each}.
+		path = []string{"."}
+	default:
+		ident := p.parseIdent()
+		if ident == "" {
+			return nil, fmt.Errorf("expected identifier at %q", p.input[p.pos:])
+		}
+		path = []string{ident}
+	}
+	// "." and ".." may be followed directly by a key with no separating dot
+	// of their own (e.g. "..title" means "title" looked up in the parent
+	// scope), since the dots themselves already stand in for a path segment.
+	if (path[0] == "." || path[0] == "..") && p.pos < len(p.input) && isIdentChar(p.input[p.pos]) {
+		path = append(path, p.parseIdent())
+	}
+	for p.consume('.') {
+		next := p.parseIdent()
+		if next == "" {
+			return nil, fmt.Errorf("expected identifier after '.'")
+		}
+		path = append(path, next)
+	}
+	expr := &PlaceholderExpr{Kind: ExprPath, Path: path}
+
+	if p.consume('[') {
+		start, err := p.parseInt()
+		if err != nil {
+			return nil, err
+		}
+		if p.consume2('.', '.') {
+			end, err := p.parseInt()
+			if err != nil {
+				return nil, err
+			}
+			expr = &PlaceholderExpr{Kind: ExprRange, Target: expr, RangeStart: start, RangeEnd: end}
+		} else {
+			expr = &PlaceholderExpr{Kind: ExprIndex, Target: expr, Index: start}
+		}
+		if !p.consume(']') {
+			return nil, fmt.Errorf("expected ']'")
+		}
+	}
+	return expr, nil
+}
+
+func (p *exprParser) parseIdent() string {
+	start := p.pos
+	for p.pos < len(p.input) && isIdentChar(p.input[p.pos]) {
+		p.pos++
+	}
+	return p.input[start:p.pos]
+}
+
+func isIdentChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-'
+}
+
+func (p *exprParser) parseInt() (int, error) {
+	start := p.pos
+	for p.pos < len(p.input) && p.input[p.pos] >= '0' && p.input[p.pos] <= '9' {
+		p.pos++
+	}
+	if p.pos == start {
+		return 0, fmt.Errorf("expected number at %q", p.input[start:])
+	}
+	return strconv.Atoi(p.input[start:p.pos])
+}
+
+func (p *exprParser) skipSpace() {
+	for p.pos < len(p.input) && p.input[p.pos] == ' ' {
+		p.pos++
+	}
+}
+
+func (p *exprParser) consume(c byte) bool {
+	if p.pos < len(p.input) && p.input[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *exprParser) consume2(a, b byte) bool {
+	if p.pos+1 < len(p.input) && p.input[p.pos] == a && p.input[p.pos+1] == b {
+		p.pos += 2
+		return true
+	}
+	return false
+}
+
+func (p *exprParser) peekAny(chars ...byte) bool {
+	if p.pos >= len(p.input) {
+		return false
+	}
+	for _, c := range chars {
+		if p.input[p.pos] == c {
+			return true
+		}
+	}
+	return false
+}
+
+// Scope is a chain of PlaceholderMap lookup contexts, used to evaluate
+// expressions inside a loop/conditional block. Each iteration of an
+// `{#each}` block pushes a child Scope so that `.` refers to the current
+// element and `..` refers to the enclosing scope's data, while ordinary
+// keys fall back to the parent scope when not found locally.
+type Scope struct {
+	// data is the scope's current value. For the root scope and for any
+	// `{#each}`/`{#row}` iteration over a slice of maps, it holds a
+	// PlaceholderMap (or map[string]interface{}); for an iteration over a
+	// slice of primitives, it holds the element itself, so that `.` alone
+	// resolves straight to it.
+	data   interface{}
+	parent *Scope
+}
+
+// NewScope returns a root Scope over data, with no parent.
+func NewScope(data PlaceholderMap) *Scope {
+	return &Scope{data: data}
+}
+
+// Child returns a new Scope over data whose parent is s, for evaluating the
+// body of a block iteration. data is typically a PlaceholderMap, but may be
+// any value (e.g. a string or number) when iterating a slice of primitives.
+func (s *Scope) Child(data interface{}) *Scope {
+	return &Scope{data: data, parent: s}
+}
+
+// Resolve looks up path against the scope chain. A path of exactly "." or
+// ".." resolves to the current or parent scope's whole data value
+// respectively; any longer path continues the lookup from there, requiring
+// that data to be a map. Any other path is looked up in s.data, falling
+// back to the parent scope if the key isn't found locally.
+func (s *Scope) Resolve(path []string) (interface{}, error) {
+	if len(path) == 0 {
+		return nil, fmt.Errorf("docx: empty path")
+	}
+	switch path[0] {
+	case ".":
+		if len(path) == 1 {
+			return s.data, nil
+		}
+		return lookupIn(s.data, path[1:])
+	case "..":
+		if s.parent == nil {
+			return nil, fmt.Errorf("docx: %q has no parent scope", strings.Join(path, "."))
+		}
+		if len(path) == 1 {
+			return s.parent.data, nil
+		}
+		return s.parent.Resolve(path[1:])
+	default:
+		value, err := lookupIn(s.data, path)
+		if err != nil && s.parent != nil {
+			return s.parent.Resolve(path)
+		}
+		return value, err
+	}
+}
+
+// lookupIn walks path through nested maps starting at data, e.g.
+// lookupIn(data, []string{"user", "name"}) looks up data["user"]["name"].
+// data may be a PlaceholderMap, a map[string]interface{}, or any other
+// value if path is empty.
+func lookupIn(data interface{}, path []string) (interface{}, error) {
+	current := data
+	for _, key := range path {
+		m, ok := asMap(current)
+		if !ok {
+			return nil, fmt.Errorf("docx: cannot look up %q on non-map value", key)
+		}
+		value, ok := m[key]
+		if !ok {
+			return nil, fmt.Errorf("docx: unknown key %q", key)
+		}
+		current = value
+	}
+	return current, nil
+}
+
+// FilterFunc transforms a value inside a pipeline stage. in is the value
+// produced by the previous stage (or the looked-up value for the first
+// stage) and args are the filter's literal arguments, e.g. ["%.2f"] for
+// `format:"%.2f"`.
+type FilterFunc func(in interface{}, args []string) (interface{}, error)
+
+// FilterRegistry holds the named filters available to PlaceholderExpr.Eval.
+// The zero value is not ready to use; call NewFilterRegistry.
+type FilterRegistry struct {
+	filters map[string]FilterFunc
+}
+
+// NewFilterRegistry returns a FilterRegistry pre-populated with the default
+// filters: upper, lower, title, trim, default, join, format and date.
+func NewFilterRegistry() *FilterRegistry {
+	r := &FilterRegistry{filters: make(map[string]FilterFunc)}
+	r.Register("upper", filterUpper)
+	r.Register("lower", filterLower)
+	r.Register("title", filterTitle)
+	r.Register("trim", filterTrim)
+	r.Register("default", filterDefault)
+	r.Register("join", filterJoin)
+	r.Register("format", filterFormat)
+	r.Register("date", filterDate)
+	return r
+}
+
+// Register adds or replaces the filter called name.
+func (r *FilterRegistry) Register(name string, fn FilterFunc) {
+	r.filters[name] = fn
+}
+
+// Lookup returns the filter called name, or false if none is registered.
+func (r *FilterRegistry) Lookup(name string) (FilterFunc, bool) {
+	fn, ok := r.filters[name]
+	return fn, ok
+}
+
+// DefaultFilterRegistry is the FilterRegistry used by PlaceholderExpr.Eval
+// when no registry is given explicitly.
+var DefaultFilterRegistry = NewFilterRegistry()
+
+// RegisterFilter registers fn as name in DefaultFilterRegistry.
+func RegisterFilter(name string, fn FilterFunc) {
+	DefaultFilterRegistry.Register(name, fn)
+}
+
+func filterUpper(in interface{}, _ []string) (interface{}, error) {
+	return strings.ToUpper(fmt.Sprint(in)), nil
+}
+
+func filterLower(in interface{}, _ []string) (interface{}, error) {
+	return strings.ToLower(fmt.Sprint(in)), nil
+}
+
+func filterTitle(in interface{}, _ []string) (interface{}, error) {
+	return strings.Title(fmt.Sprint(in)), nil
+}
+
+func filterTrim(in interface{}, _ []string) (interface{}, error) {
+	return strings.TrimSpace(fmt.Sprint(in)), nil
+}
+
+func filterDefault(in interface{}, args []string) (interface{}, error) {
+	if in == nil || in == "" {
+		if len(args) == 0 {
+			return nil, fmt.Errorf("default filter requires one argument")
+		}
+		return args[0], nil
+	}
+	return in, nil
+}
+
+func filterJoin(in interface{}, args []string) (interface{}, error) {
+	sep := ","
+	if len(args) > 0 {
+		sep = args[0]
+	}
+	items, ok := toSlice(in)
+	if !ok {
+		return nil, fmt.Errorf("join filter requires a slice value")
+	}
+	parts := make([]string, len(items))
+	for i, item := range items {
+		parts[i] = fmt.Sprint(item)
+	}
+	return strings.Join(parts, sep), nil
+}
+
+func filterFormat(in interface{}, args []string) (interface{}, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("format filter requires a format string argument")
+	}
+	return fmt.Sprintf(args[0], in), nil
+}
+
+func filterDate(in interface{}, args []string) (interface{}, error) {
+	layout := "2006-01-02"
+	if len(args) > 0 {
+		layout = args[0]
+	}
+	t, ok := in.(interface{ Format(string) string })
+	if !ok {
+		return nil, fmt.Errorf("date filter requires a value with a Format(string) string method")
+	}
+	return t.Format(layout), nil
+}
+
+// Eval evaluates the expression against data, using registry to resolve
+// filter names. If registry is nil, DefaultFilterRegistry is used.
+func (e *PlaceholderExpr) Eval(data PlaceholderMap, registry *FilterRegistry) (interface{}, error) {
+	return e.EvalScope(NewScope(data), registry)
+}
+
+// EvalScope evaluates the expression against scope, using registry to
+// resolve filter names. If registry is nil, DefaultFilterRegistry is used.
+// A path of "." or ".." resolves against scope's current or parent data
+// instead of being looked up as an ordinary key; this is what lets a block's
+// body refer to the current/enclosing loop element. Eval uses the same
+// lookup on a root Scope, where "." is the whole data map and ".." is an
+// error, since a root Scope has no parent.
+func (e *PlaceholderExpr) EvalScope(scope *Scope, registry *FilterRegistry) (interface{}, error) {
+	if registry == nil {
+		registry = DefaultFilterRegistry
+	}
+	switch e.Kind {
+	case ExprLiteral:
+		return e.Literal, nil
+
+	case ExprPath:
+		return scope.Resolve(e.Path)
+
+	case ExprIndex:
+		target, err := e.Target.EvalScope(scope, registry)
+		if err != nil {
+			return nil, err
+		}
+		items, ok := toSlice(target)
+		if !ok {
+			return nil, fmt.Errorf("docx: cannot index non-slice value %v", target)
+		}
+		if e.Index < 0 || e.Index >= len(items) {
+			return nil, fmt.Errorf("docx: index %d out of range (len %d)", e.Index, len(items))
+		}
+		return items[e.Index], nil
+
+	case ExprRange:
+		target, err := e.Target.EvalScope(scope, registry)
+		if err != nil {
+			return nil, err
+		}
+		items, ok := toSlice(target)
+		if !ok {
+			return nil, fmt.Errorf("docx: cannot range over non-slice value %v", target)
+		}
+		if e.RangeStart < 0 || e.RangeEnd > len(items) || e.RangeStart > e.RangeEnd {
+			return nil, fmt.Errorf("docx: range [%d..%d] out of bounds (len %d)", e.RangeStart, e.RangeEnd, len(items))
+		}
+		return items[e.RangeStart:e.RangeEnd], nil
+
+	case ExprPipeline:
+		value, err := e.Target.EvalScope(scope, registry)
+		if err != nil {
+			return nil, err
+		}
+		for _, filter := range e.Filters {
+			fn, ok := registry.Lookup(filter.Name)
+			if !ok {
+				return nil, fmt.Errorf("docx: unknown filter %q", filter.Name)
+			}
+			value, err = fn(value, filter.Args)
+			if err != nil {
+				return nil, fmt.Errorf("docx: filter %q: %w", filter.Name, err)
+			}
+		}
+		return value, nil
+
+	case ExprTernary:
+		cond, err := e.Cond.EvalScope(scope, registry)
+		if err != nil {
+			return nil, err
+		}
+		if isTruthy(cond) {
+			return e.Then.EvalScope(scope, registry)
+		}
+		return e.Else.EvalScope(scope, registry)
+	}
+	return nil, fmt.Errorf("docx: unknown expression kind %v", e.Kind)
+}
+
+// toSlice returns v as a []interface{}, accepting both that type directly and
+// any other concrete slice/array type (e.g. []string, []int) via reflection,
+// since a PlaceholderMap is just as likely to be populated with a plain Go
+// slice as with a pre-boxed []interface{}.
+func toSlice(v interface{}) ([]interface{}, bool) {
+	if items, ok := v.([]interface{}); ok {
+		return items, true
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, false
+	}
+	items := make([]interface{}, rv.Len())
+	for i := range items {
+		items[i] = rv.Index(i).Interface()
+	}
+	return items, true
+}
+
+// asMap returns v as a map[string]interface{}, accepting both that type and
+// PlaceholderMap, since nested values are just as likely to be built as one
+// as the other (e.g. `PlaceholderMap{"user": PlaceholderMap{"name": "Bob"}}`).
+func asMap(v interface{}) (map[string]interface{}, bool) {
+	switch m := v.(type) {
+	case map[string]interface{}:
+		return m, true
+	case PlaceholderMap:
+		return m, true
+	}
+	return nil, false
+}
+
+func isTruthy(v interface{}) bool {
+	switch val := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return val
+	case string:
+		return val != ""
+	default:
+		return true
+	}
+}
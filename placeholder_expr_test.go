@@ -0,0 +1,157 @@
+package docx
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestPlaceholderExprEvalNestedPlaceholderMap(t *testing.T) {
+	expr, err := ParsePlaceholderExpr("user.name")
+	if err != nil {
+		t.Fatalf("ParsePlaceholderExpr returned error: %v", err)
+	}
+
+	data := PlaceholderMap{
+		"user": PlaceholderMap{
+			"name": "Bob",
+			"address": PlaceholderMap{
+				"city": "Berlin",
+			},
+		},
+	}
+
+	got, err := expr.Eval(data, nil)
+	if err != nil {
+		t.Fatalf("Eval returned error: %v", err)
+	}
+	if got != "Bob" {
+		t.Fatalf("Eval(%q) = %v, want %q", "user.name", got, "Bob")
+	}
+
+	deepExpr, err := ParsePlaceholderExpr("user.address.city")
+	if err != nil {
+		t.Fatalf("ParsePlaceholderExpr returned error: %v", err)
+	}
+	got, err = deepExpr.Eval(data, nil)
+	if err != nil {
+		t.Fatalf("Eval returned error: %v", err)
+	}
+	if got != "Berlin" {
+		t.Fatalf("Eval(%q) = %v, want %q", "user.address.city", got, "Berlin")
+	}
+}
+
+func TestPlaceholderExprEvalMixedMapTypes(t *testing.T) {
+	expr, err := ParsePlaceholderExpr("user.name")
+	if err != nil {
+		t.Fatalf("ParsePlaceholderExpr returned error: %v", err)
+	}
+
+	// a nested map[string]interface{} (as produced by e.g. encoding/json)
+	// must work just as well as a nested PlaceholderMap.
+	data := PlaceholderMap{
+		"user": map[string]interface{}{
+			"name": "Alice",
+		},
+	}
+
+	got, err := expr.Eval(data, nil)
+	if err != nil {
+		t.Fatalf("Eval returned error: %v", err)
+	}
+	if got != "Alice" {
+		t.Fatalf("Eval(%q) = %v, want %q", "user.name", got, "Alice")
+	}
+}
+
+// TestPlaceholderExprIndexAndRangeOnTypedSlice exercises the request's own
+// headline example, `{items[0..3]:join(", ")}`, against a plain []string
+// (the obvious way to populate a PlaceholderMap), not just a pre-boxed
+// []interface{}.
+func TestPlaceholderExprIndexAndRangeOnTypedSlice(t *testing.T) {
+	data := PlaceholderMap{"items": []string{"a", "b", "c", "d"}}
+
+	indexExpr, err := ParsePlaceholderExpr("items[1]")
+	if err != nil {
+		t.Fatalf("ParsePlaceholderExpr returned error: %v", err)
+	}
+	if got, err := indexExpr.Eval(data, nil); err != nil || got != "b" {
+		t.Errorf(`Eval("items[1]") = %v, %v; want "b", nil`, got, err)
+	}
+
+	joinExpr, err := ParsePlaceholderExpr(`items[0..3]:join(", ")`)
+	if err != nil {
+		t.Fatalf("ParsePlaceholderExpr returned error: %v", err)
+	}
+	got, err := joinExpr.Eval(data, nil)
+	if err != nil {
+		t.Fatalf("Eval returned error: %v", err)
+	}
+	if got != "a, b, c" {
+		t.Errorf(`Eval("items[0..3]:join(\", \")") = %v, want %q`, got, "a, b, c")
+	}
+}
+
+func TestPlaceholderExprIndexOutOfRange(t *testing.T) {
+	expr, err := ParsePlaceholderExpr("items[5]")
+	if err != nil {
+		t.Fatalf("ParsePlaceholderExpr returned error: %v", err)
+	}
+	if _, err := expr.Eval(PlaceholderMap{"items": []interface{}{"a", "b"}}, nil); err == nil {
+		t.Error("Eval returned nil error, want an out-of-range error")
+	}
+}
+
+func TestPlaceholderExprFilterPipeline(t *testing.T) {
+	expr, err := ParsePlaceholderExpr(`name|trim|upper|default:"ANON"`)
+	if err != nil {
+		t.Fatalf("ParsePlaceholderExpr returned error: %v", err)
+	}
+	got, err := expr.Eval(PlaceholderMap{"name": "  bob  "}, nil)
+	if err != nil {
+		t.Fatalf("Eval returned error: %v", err)
+	}
+	if got != "BOB" {
+		t.Errorf("Eval = %v, want %q", got, "BOB")
+	}
+
+	got, err = expr.Eval(PlaceholderMap{"name": ""}, nil)
+	if err != nil {
+		t.Fatalf("Eval returned error: %v", err)
+	}
+	if got != "ANON" {
+		t.Errorf(`Eval("") = %v, want "ANON" (default filter)`, got)
+	}
+}
+
+func TestPlaceholderExprCustomFilter(t *testing.T) {
+	RegisterFilter("shout", func(in interface{}, _ []string) (interface{}, error) {
+		return fmt.Sprintf("%v!!!", in), nil
+	})
+
+	expr, err := ParsePlaceholderExpr("greeting|shout")
+	if err != nil {
+		t.Fatalf("ParsePlaceholderExpr returned error: %v", err)
+	}
+	got, err := expr.Eval(PlaceholderMap{"greeting": "hi"}, nil)
+	if err != nil {
+		t.Fatalf("Eval returned error: %v", err)
+	}
+	if got != "hi!!!" {
+		t.Errorf(`Eval = %v, want "hi!!!"`, got)
+	}
+}
+
+func TestPlaceholderExprTernary(t *testing.T) {
+	expr, err := ParsePlaceholderExpr(`active?"yes":"no"`)
+	if err != nil {
+		t.Fatalf("ParsePlaceholderExpr returned error: %v", err)
+	}
+
+	if got, err := expr.Eval(PlaceholderMap{"active": true}, nil); err != nil || got != "yes" {
+		t.Errorf(`Eval(active=true) = %v, %v; want "yes", nil`, got, err)
+	}
+	if got, err := expr.Eval(PlaceholderMap{"active": false}, nil); err != nil || got != "no" {
+		t.Errorf(`Eval(active=false) = %v, %v; want "no", nil`, got, err)
+	}
+}
@@ -0,0 +1,118 @@
+package docx
+
+// PlaceholderScanner scans a document's runs for placeholders one at a time,
+// in the style of bufio.Scanner: call Scan in a loop, and read the result
+// with Placeholder after each call that returns true. Unlike ParsePlaceholders,
+// it never materializes the full placeholder slice up front, holding only the
+// currently-open fragment (if any) in memory, which makes it suitable for
+// large, multi-MB documents.
+type PlaceholderScanner struct {
+	runs     []*Run
+	docBytes []byte
+	cfg      *ParserConfig
+
+	runIdx int // index into runs of the next run to consume
+
+	pending []*Placeholder // placeholders found while consuming runs, not yet returned by Scan
+	current *Placeholder   // the placeholder returned by the most recent Scan
+
+	open    *Placeholder // fragments of a placeholder left unclosed across runs
+	hasOpen bool
+
+	err error
+}
+
+// NewPlaceholderScanner returns a PlaceholderScanner over runs. cfg controls
+// the delimiters recognised by the scanner; if cfg is nil, DefaultParserConfig
+// is used.
+func NewPlaceholderScanner(runs DocumentRuns, docBytes []byte, cfg *ParserConfig) *PlaceholderScanner {
+	if cfg == nil {
+		cfg = DefaultParserConfig()
+	}
+	return &PlaceholderScanner{
+		runs:     runs.WithText(),
+		docBytes: docBytes,
+		cfg:      cfg,
+		open:     new(Placeholder),
+	}
+}
+
+// Scan advances the scanner to the next placeholder, which will then be
+// available through Placeholder. It returns false when the scan stops,
+// either by reaching the end of the runs or an error; Err returns the error,
+// if any.
+func (s *PlaceholderScanner) Scan() bool {
+	for len(s.pending) == 0 && s.runIdx < len(s.runs) {
+		s.consumeRun(s.runs[s.runIdx])
+		s.runIdx++
+	}
+	if len(s.pending) == 0 {
+		return false
+	}
+	s.current, s.pending = s.pending[0], s.pending[1:]
+	return true
+}
+
+// Placeholder returns the placeholder produced by the most recent call to
+// Scan that returned true.
+func (s *PlaceholderScanner) Placeholder() *Placeholder {
+	return s.current
+}
+
+// Err returns the first error encountered while scanning, if any.
+func (s *PlaceholderScanner) Err() error {
+	return s.err
+}
+
+// consumeRun processes a single run, appending any placeholders it completes
+// to s.pending and updating s.open for anything left unclosed.
+func (s *PlaceholderScanner) consumeRun(run *Run) {
+	runText := run.GetText(s.docBytes)
+	openPos, closePos := s.cfg.findDelimiterPositions(runText, run.Text.StartTag.End)
+
+	if len(openPos) == len(closePos) && len(openPos) != 0 {
+		s.pending = append(s.pending, assembleFullPlaceholders(run, openPos, closePos)...)
+		return
+	}
+
+	if len(openPos) > len(closePos) {
+		// only as many opens as there are closes can be paired; any further
+		// stray opens before the last one are not yielded.
+		matched := len(closePos)
+		s.pending = append(s.pending, assembleFullPlaceholders(run, openPos[:matched], closePos)...)
+
+		unclosedOpenPos := openPos[len(openPos)-1]
+		s.open.Fragments = append(s.open.Fragments, &PlaceholderFragment{
+			Position: Position{Start: int64(unclosedOpenPos), End: int64(len(runText))},
+			Number:   0,
+			Run:      run,
+		})
+		s.hasOpen = true
+		return
+	}
+
+	if len(openPos) < len(closePos) {
+		s.pending = append(s.pending, assembleFullPlaceholders(run, openPos, closePos[:len(closePos)-1])...)
+
+		if len(closePos) == 1 && s.hasOpen {
+			s.open.Fragments = append(s.open.Fragments, &PlaceholderFragment{
+				Position: Position{Start: 0, End: int64(closePos[0])},
+				Number:   len(s.open.Fragments) + 1,
+				Run:      run,
+			})
+			s.pending = append(s.pending, s.open)
+			s.open = new(Placeholder)
+			s.hasOpen = false
+		}
+		return
+	}
+
+	// no delimiters at all; only relevant if a placeholder is currently open
+	if s.hasOpen {
+		s.open.Fragments = append(s.open.Fragments, &PlaceholderFragment{
+			Position: Position{Start: 0, End: int64(len(runText))},
+			Number:   len(s.open.Fragments) + 1,
+			Run:      run,
+		})
+	}
+}
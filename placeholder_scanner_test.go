@@ -0,0 +1,76 @@
+package docx
+
+import "testing"
+
+// newRun builds a *Run whose text spans docBytes[start:end]. OpenTag/CloseTag
+// aren't read by the scanner or validator and are left zero.
+func newRun(start, end int64) *Run {
+	r := &Run{}
+	r.Text.StartTag.End = start
+	r.Text.EndTag.Start = end
+	return r
+}
+
+func TestPlaceholderScannerSingleRun(t *testing.T) {
+	docBytes := []byte("Hello {name}, welcome to {place}.")
+	runs := DocumentRuns{newRun(0, int64(len(docBytes)))}
+
+	scanner := NewPlaceholderScanner(runs, docBytes, nil)
+
+	var got []string
+	for scanner.Scan() {
+		got = append(got, scanner.Placeholder().Text(docBytes))
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("Err() = %v, want nil", err)
+	}
+	want := []string{"{name}", "{place}"}
+	if len(got) != len(want) {
+		t.Fatalf("scanned %d placeholders, want %d (got %v)", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("placeholder %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+// TestPlaceholderScannerAcrossRuns exercises the incremental-scan path's
+// equivalent of ParsePlaceholders' cross-run fragment assembly: a single
+// placeholder whose open and close delimiters land in different runs, the
+// way WordprocessingML often splits one logical run of text.
+func TestPlaceholderScannerAcrossRuns(t *testing.T) {
+	docBytes := []byte("say {na me} now")
+	runs := DocumentRuns{
+		newRun(0, 8),                    // "say {na "
+		newRun(8, int64(len(docBytes))), // "me} now"
+	}
+
+	scanner := NewPlaceholderScanner(runs, docBytes, nil)
+	if !scanner.Scan() {
+		t.Fatalf("Scan() = false, want true (one placeholder split across two runs)")
+	}
+	got := scanner.Placeholder().Text(docBytes)
+	if got != "{na me}" {
+		t.Errorf("Placeholder().Text() = %q, want %q", got, "{na me}")
+	}
+	if scanner.Scan() {
+		t.Errorf("second Scan() = true, want false (no more placeholders)")
+	}
+	if err := scanner.Err(); err != nil {
+		t.Errorf("Err() = %v, want nil", err)
+	}
+}
+
+func TestPlaceholderScannerNoPlaceholders(t *testing.T) {
+	docBytes := []byte("nothing to see here")
+	runs := DocumentRuns{newRun(0, int64(len(docBytes)))}
+
+	scanner := NewPlaceholderScanner(runs, docBytes, nil)
+	if scanner.Scan() {
+		t.Errorf("Scan() = true, want false (no delimiters in text)")
+	}
+	if err := scanner.Err(); err != nil {
+		t.Errorf("Err() = %v, want nil", err)
+	}
+}
@@ -0,0 +1,65 @@
+package docx
+
+import "testing"
+
+func TestParsePlaceholdersSimple(t *testing.T) {
+	docBytes := []byte("Hello {name}, welcome to {place}.")
+	runs := DocumentRuns{newRun(0, int64(len(docBytes)))}
+
+	placeholders := ParsePlaceholders(runs, docBytes, nil)
+
+	want := []string{"{name}", "{place}"}
+	if len(placeholders) != len(want) {
+		t.Fatalf("got %d placeholders, want %d (got %v)", len(placeholders), len(want), placeholders)
+	}
+	for i, p := range placeholders {
+		if got := p.Text(docBytes); got != want[i] {
+			t.Errorf("placeholder %d = %q, want %q", i, got, want[i])
+		}
+	}
+}
+
+// TestParsePlaceholdersMultipleStrayOpens exercises a single run with more
+// than one never-closed open delimiter (e.g. literal text containing "{ { {"
+// that happens to collide with the delimiter, such as a JSON/code sample).
+// It must not panic, and since none of the stray opens have a matching
+// close, no placeholders should be produced.
+func TestParsePlaceholdersMultipleStrayOpens(t *testing.T) {
+	docBytes := []byte("{ { {")
+	runs := DocumentRuns{newRun(0, int64(len(docBytes)))}
+
+	placeholders := ParsePlaceholders(runs, docBytes, nil)
+	if len(placeholders) != 0 {
+		t.Errorf("got %d placeholders, want 0 (got %v)", len(placeholders), placeholders)
+	}
+}
+
+// TestParsePlaceholdersMultipleStrayCloses mirrors the stray-opens case for
+// excess closing delimiters with no opens at all in the run: it must not
+// panic, and since none of the closes have a matching open, no placeholders
+// should be produced.
+func TestParsePlaceholdersMultipleStrayCloses(t *testing.T) {
+	docBytes := []byte("} } }")
+	runs := DocumentRuns{newRun(0, int64(len(docBytes)))}
+
+	placeholders := ParsePlaceholders(runs, docBytes, nil)
+	if len(placeholders) != 0 {
+		t.Errorf("got %d placeholders, want 0 (got %v)", len(placeholders), placeholders)
+	}
+}
+
+// TestParsePlaceholdersSplitAcrossRuns exercises a placeholder whose open
+// and close delimiters land in different runs, carried forward via the
+// unclosed-placeholder state.
+func TestParsePlaceholdersSplitAcrossRuns(t *testing.T) {
+	docBytes := []byte("say {na me} now")
+	runs := DocumentRuns{
+		newRun(0, 8),
+		newRun(8, int64(len(docBytes))),
+	}
+
+	placeholders := ParsePlaceholders(runs, docBytes, nil)
+	if len(placeholders) != 1 || placeholders[0].Text(docBytes) != "{na me}" {
+		t.Errorf("got %v, want a single {na me} placeholder", placeholders)
+	}
+}
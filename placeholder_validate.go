@@ -0,0 +1,229 @@
+package docx
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PlaceholderErrorKind identifies the kind of problem a PlaceholderError
+// describes.
+type PlaceholderErrorKind int
+
+const (
+	// UnclosedDelimiter means an open delimiter was never followed by a matching close delimiter.
+	// ParseBlocks also reports it for an opening block directive that is never closed.
+	UnclosedDelimiter PlaceholderErrorKind = iota
+	// UnopenedDelimiter means a close delimiter was found with no matching open delimiter before it.
+	// ParseBlocks also reports it for a closing or `{else}` directive with no matching open block.
+	UnopenedDelimiter
+	// EmptyPlaceholder means a placeholder's body, with delimiters stripped, is empty.
+	EmptyPlaceholder
+	// UnknownKey means a placeholder references a key that ParsePlaceholderExpr/Eval could not resolve.
+	UnknownKey
+	// NestedPlaceholder means an open delimiter was found before the preceding one was closed.
+	// ParseBlocks also reports it for an opening block directive found inside an open block.
+	NestedPlaceholder
+)
+
+func (k PlaceholderErrorKind) String() string {
+	switch k {
+	case UnclosedDelimiter:
+		return "UnclosedDelimiter"
+	case UnopenedDelimiter:
+		return "UnopenedDelimiter"
+	case EmptyPlaceholder:
+		return "EmptyPlaceholder"
+	case UnknownKey:
+		return "UnknownKey"
+	case NestedPlaceholder:
+		return "NestedPlaceholder"
+	default:
+		return "Unknown"
+	}
+}
+
+// PlaceholderError describes a single problem found while validating a
+// template. Offset is the absolute byte offset into the document where the
+// problem was found. RunIndex is the index, among the runs returned by
+// DocumentRuns.WithText, of the run the problem was found in; only the
+// errors produced by Validate set it, and the UnknownKey errors added by
+// ValidateAgainst and the errors from ParseBlocks leave it zero.
+// ParagraphIndex is not set by any function in this package and is always
+// zero.
+type PlaceholderError struct {
+	Kind           PlaceholderErrorKind
+	Offset         int64
+	RunIndex       int
+	ParagraphIndex int
+	Snippet        string // surrounding text, for inclusion in error messages
+
+	// Wrapped is a lower-level error this PlaceholderError was derived from,
+	// if any (e.g. an error returned by PlaceholderExpr.Eval).
+	Wrapped error
+}
+
+func (e *PlaceholderError) Error() string {
+	msg := fmt.Sprintf("docx: %s at offset %d (run %d, paragraph %d): %q", e.Kind, e.Offset, e.RunIndex, e.ParagraphIndex, e.Snippet)
+	if e.Wrapped != nil {
+		msg += ": " + e.Wrapped.Error()
+	}
+	return msg
+}
+
+// Unwrap returns the lower-level error this PlaceholderError was derived
+// from, if any, so that errors.Is/errors.As can see through it.
+func (e *PlaceholderError) Unwrap() error {
+	return e.Wrapped
+}
+
+// PlaceholderErrors joins multiple PlaceholderError values into a single
+// error, in the same spirit as errors.Join: Error concatenates every
+// message on its own line, and Unwrap exposes the individual errors so
+// errors.Is/errors.As can inspect them.
+type PlaceholderErrors []*PlaceholderError
+
+func (e PlaceholderErrors) Error() string {
+	var b strings.Builder
+	for i, err := range e {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteString(err.Error())
+	}
+	return b.String()
+}
+
+// Unwrap exposes the individual errors so errors.Is and errors.As can
+// inspect each one.
+func (e PlaceholderErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+	return errs
+}
+
+// Validate scans runs for malformed placeholders and returns one
+// PlaceholderError per problem found, in document order. An empty result
+// means the template is well-formed with respect to delimiter balancing and
+// emptiness; it does not evaluate expressions against a PlaceholderMap, so
+// UnknownKey errors are only produced by ValidateAgainst.
+func Validate(runs DocumentRuns, docBytes []byte, cfg *ParserConfig) []*PlaceholderError {
+	if cfg == nil {
+		cfg = DefaultParserConfig()
+	}
+
+	var errs []*PlaceholderError
+	runList := runs.WithText()
+
+	openRun, openOffset := -1, int64(-1)
+	hasOpen := false
+
+	for runIdx, run := range runList {
+		runText := run.GetText(docBytes)
+		for _, m := range cfg.scanDelimiters(runText) {
+			offset := run.Text.StartTag.End + int64(m.pos)
+			if cfg.isRaw(offset) {
+				continue
+			}
+			if m.open {
+				if hasOpen {
+					errs = append(errs, &PlaceholderError{
+						Kind:     NestedPlaceholder,
+						Offset:   offset,
+						RunIndex: runIdx,
+						Snippet:  snippet(docBytes, offset),
+					})
+				}
+				hasOpen, openRun, openOffset = true, runIdx, offset
+				continue
+			}
+			// close delimiter
+			if !hasOpen {
+				errs = append(errs, &PlaceholderError{
+					Kind:     UnopenedDelimiter,
+					Offset:   offset,
+					RunIndex: runIdx,
+					Snippet:  snippet(docBytes, offset),
+				})
+				continue
+			}
+			closeEnd := offset + int64(m.length)
+			body := string(docBytes[openOffset:closeEnd])
+			if isEmptyPlaceholderBody(body, cfg) {
+				errs = append(errs, &PlaceholderError{
+					Kind:     EmptyPlaceholder,
+					Offset:   openOffset,
+					RunIndex: openRun,
+					Snippet:  snippet(docBytes, openOffset),
+				})
+			}
+			hasOpen = false
+		}
+	}
+
+	if hasOpen {
+		errs = append(errs, &PlaceholderError{
+			Kind:     UnclosedDelimiter,
+			Offset:   openOffset,
+			RunIndex: openRun,
+			Snippet:  snippet(docBytes, openOffset),
+		})
+	}
+
+	return errs
+}
+
+// ValidateAgainst runs Validate and additionally resolves each well-formed
+// placeholder's expression against data, appending an UnknownKey error for
+// any that fail to resolve. It is the check a strict-mode Document.Replace
+// should run before substituting any placeholders, once Document.Replace
+// grows a strict mode; Document.Replace does not call this yet.
+func ValidateAgainst(runs DocumentRuns, docBytes []byte, cfg *ParserConfig, data PlaceholderMap) []*PlaceholderError {
+	if cfg == nil {
+		cfg = DefaultParserConfig()
+	}
+	errs := Validate(runs, docBytes, cfg)
+
+	for _, placeholder := range ParsePlaceholders(runs, docBytes, cfg) {
+		body := cfg.StripDelimiters(placeholder.Text(docBytes))
+		expr, err := ParsePlaceholderExpr(body)
+		if err != nil {
+			continue // already reported as malformed by Validate, or not an expression placeholder
+		}
+		if _, err := expr.Eval(data, nil); err != nil {
+			errs = append(errs, &PlaceholderError{
+				Kind:    UnknownKey,
+				Offset:  placeholder.StartPos(),
+				Snippet: snippet(docBytes, placeholder.StartPos()),
+				Wrapped: err,
+			})
+		}
+	}
+	return errs
+}
+
+func isEmptyPlaceholderBody(body string, cfg *ParserConfig) bool {
+	for _, pair := range cfg.Delimiters {
+		if strings.HasPrefix(body, pair.Open) && strings.HasSuffix(body, pair.Close) {
+			inner := body[len(pair.Open) : len(body)-len(pair.Close)]
+			return strings.TrimSpace(inner) == ""
+		}
+	}
+	return false
+}
+
+// snippet returns a short window of docBytes around offset, for inclusion in
+// error messages.
+func snippet(docBytes []byte, offset int64) string {
+	const radius = 16
+	start := offset - radius
+	if start < 0 {
+		start = 0
+	}
+	end := offset + radius
+	if end > int64(len(docBytes)) {
+		end = int64(len(docBytes))
+	}
+	return string(docBytes[start:end])
+}
@@ -0,0 +1,58 @@
+package docx
+
+import "testing"
+
+func TestValidateDetectsEmptyAndUnclosed(t *testing.T) {
+	docBytes := []byte("Hi {name}, {} and {oops")
+	runs := DocumentRuns{newRun(0, int64(len(docBytes)))}
+
+	errs := Validate(runs, docBytes, nil)
+
+	want := []PlaceholderErrorKind{EmptyPlaceholder, UnclosedDelimiter}
+	if len(errs) != len(want) {
+		t.Fatalf("Validate returned %d errors, want %d (got %v)", len(errs), len(want), errs)
+	}
+	for i, kind := range want {
+		if errs[i].Kind != kind {
+			t.Errorf("errs[%d].Kind = %v, want %v", i, errs[i].Kind, kind)
+		}
+	}
+}
+
+func TestValidateDetectsUnopenedDelimiter(t *testing.T) {
+	docBytes := []byte("oops} {name}")
+	runs := DocumentRuns{newRun(0, int64(len(docBytes)))}
+
+	errs := Validate(runs, docBytes, nil)
+
+	if len(errs) != 1 || errs[0].Kind != UnopenedDelimiter {
+		t.Fatalf("Validate = %v, want a single UnopenedDelimiter error", errs)
+	}
+}
+
+func TestValidateAgainstDetectsUnknownKey(t *testing.T) {
+	docBytes := []byte("Hello {name}, you are {age}.")
+	runs := DocumentRuns{newRun(0, int64(len(docBytes)))}
+
+	errs := ValidateAgainst(runs, docBytes, nil, PlaceholderMap{"name": "Bob"})
+
+	if len(errs) != 1 {
+		t.Fatalf("ValidateAgainst returned %d errors, want 1 (got %v)", len(errs), errs)
+	}
+	if errs[0].Kind != UnknownKey {
+		t.Errorf("errs[0].Kind = %v, want UnknownKey", errs[0].Kind)
+	}
+	if errs[0].Wrapped == nil {
+		t.Error("errs[0].Wrapped = nil, want the underlying Eval error wrapped")
+	}
+}
+
+func TestValidateAgainstAllKeysResolve(t *testing.T) {
+	docBytes := []byte("Hello {name}.")
+	runs := DocumentRuns{newRun(0, int64(len(docBytes)))}
+
+	errs := ValidateAgainst(runs, docBytes, nil, PlaceholderMap{"name": "Bob"})
+	if len(errs) != 0 {
+		t.Errorf("ValidateAgainst = %v, want no errors", errs)
+	}
+}